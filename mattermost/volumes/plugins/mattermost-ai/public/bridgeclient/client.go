@@ -27,13 +27,38 @@ type AppAPI interface {
 // Client is a client for the Mattermost Agents Plugin LLM Bridge API
 type Client struct {
 	httpClient http.Client
+	retry      retryConfig
 }
 
 // Post represents a single message in the conversation
 type Post struct {
-	Role    string   `json:"role"`               // user|assistant|system
+	Role    string   `json:"role"`               // user|assistant|system|tool
 	Message string   `json:"message"`            // message content
 	FileIDs []string `json:"file_ids,omitempty"` // Mattermost file IDs
+	// ToolCalls carries the tool invocations requested by the model on an assistant Post.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolCallID identifies which ToolCall this Post is a result for, on a Role: "tool" Post.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+}
+
+// ToolDefinition describes a tool the model may call, in the JSON-schema style used by LLM
+// providers for function calling.
+type ToolDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Parameters is the JSON schema for the tool's arguments. For a tool that takes no
+	// arguments, set this to an empty object (map[string]interface{}{"type": "object",
+	// "properties": map[string]interface{}{}}) rather than leaving it nil, since most
+	// providers require a schema object rather than a JSON null.
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// ToolCall is a single tool invocation requested by the model. Arguments is the JSON-encoded
+// argument object, to be unmarshaled by the caller according to the tool's Parameters schema.
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
 }
 
 // CompletionRequest represents a completion request
@@ -47,11 +72,54 @@ type CompletionRequest struct {
 	// ChannelID is the optional Mattermost channel ID context for the request.
 	// If provided along with UserID, the bridge will check both user and channel permissions.
 	ChannelID string `json:"channel_id,omitempty"`
+	// Tools lists the tools the model may call. Omit to disable tool calling. Callers that
+	// set Tools should use the *WithUsage completion methods to receive CompletionResult.ToolCalls;
+	// the plain string-returning methods discard tool calls and return an empty completion
+	// when the model requests one.
+	Tools []ToolDefinition `json:"tools,omitempty"`
+	// ToolChoice controls whether/which tool the model must call: "auto" (default), "none",
+	// or a specific tool name.
+	ToolChoice string `json:"tool_choice,omitempty"`
+}
+
+// FinishReason indicates why the model stopped generating a completion.
+type FinishReason string
+
+const (
+	FinishReasonStop          FinishReason = "stop"
+	FinishReasonLength        FinishReason = "length"
+	FinishReasonContentFilter FinishReason = "content_filter"
+	FinishReasonToolCall      FinishReason = "tool_call"
+)
+
+// Usage reports the token accounting for a single completion request.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
 // CompletionResponse represents a non-streaming completion response
 type CompletionResponse struct {
 	Completion string `json:"completion"`
+	// Usage and FinishReason are populated on a best-effort basis; not every
+	// agent or service reports them.
+	Usage        *Usage       `json:"usage,omitempty"`
+	FinishReason FinishReason `json:"finish_reason,omitempty"`
+	// ToolCalls is populated instead of Completion when FinishReason is FinishReasonToolCall.
+	// The caller is expected to execute each call locally and send the results back as
+	// Role: "tool" Posts referencing ToolCall.ID via Post.ToolCallID.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// CompletionResult is the result of a non-streaming completion request, returned alongside
+// the completion text by the *WithUsage methods so callers can do quota enforcement, cost
+// attribution, or rate-limit budgeting without discarding the response's token usage.
+type CompletionResult struct {
+	Completion   string
+	Usage        *Usage
+	FinishReason FinishReason
+	ToolCalls    []ToolCall
 }
 
 // ErrorResponse represents an error response from the API
@@ -86,16 +154,22 @@ type ServicesResponse struct {
 }
 
 // NewClient creates a new LLM Bridge API client from a plugin's API interface.
-func NewClient(api PluginAPI) *Client {
-	client := &Client{}
+func NewClient(api PluginAPI, opts ...ClientOption) *Client {
+	client := &Client{retry: defaultRetryConfig()}
 	client.httpClient.Transport = &pluginAPIRoundTripper{api}
+	for _, opt := range opts {
+		opt(client)
+	}
 	return client
 }
 
 // NewClientFromApp creates a new LLM Bridge API client from the Mattermost server app layer.
 // The userID is used for inter-plugin request authentication.
-func NewClientFromApp(api AppAPI, userID string) *Client {
-	client := &Client{}
+func NewClientFromApp(api AppAPI, userID string, opts ...ClientOption) *Client {
+	client := &Client{retry: defaultRetryConfig()}
 	client.httpClient.Transport = &appAPIRoundTripper{api, userID}
+	for _, opt := range opts {
+		opt(client)
+	}
 	return client
 }
@@ -6,6 +6,7 @@ package bridgeclient
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,104 +18,216 @@ import (
 
 // AgentCompletion makes a non-streaming completion request to a specific agent by Bot ID.
 // The agent parameter should be the Mattermost Bot User ID (an immutable identifier).
+// If request.Tools is set, use AgentCompletionWithUsage instead to receive requested tool
+// calls; this method discards them and returns an empty completion in that case.
 func (c *Client) AgentCompletion(agent string, request CompletionRequest) (string, error) {
+	return c.AgentCompletionContext(context.Background(), agent, request)
+}
+
+// AgentCompletionContext is AgentCompletion with a context for cancellation and deadlines.
+func (c *Client) AgentCompletionContext(ctx context.Context, agent string, request CompletionRequest) (string, error) {
 	url := fmt.Sprintf("/%s/bridge/v1/completion/agent/%s/nostream", aiPluginID, agent)
-	return c.doCompletionRequest(url, request)
+	return c.doCompletionRequest(ctx, url, request)
+}
+
+// AgentCompletionWithUsage is AgentCompletion but also returns token usage and finish-reason
+// metadata alongside the completion text.
+func (c *Client) AgentCompletionWithUsage(agent string, request CompletionRequest) (*CompletionResult, error) {
+	return c.AgentCompletionWithUsageContext(context.Background(), agent, request)
+}
+
+// AgentCompletionWithUsageContext is AgentCompletionWithUsage with a context for cancellation and deadlines.
+func (c *Client) AgentCompletionWithUsageContext(ctx context.Context, agent string, request CompletionRequest) (*CompletionResult, error) {
+	url := fmt.Sprintf("/%s/bridge/v1/completion/agent/%s/nostream", aiPluginID, agent)
+	return c.doCompletionRequestWithUsage(ctx, url, request)
 }
 
 // ServiceCompletion makes a non-streaming completion request to a specific service.
 // The service parameter can be either a service ID or name (e.g., "openai", "anthropic").
+// If request.Tools is set, use ServiceCompletionWithUsage instead to receive requested tool
+// calls; this method discards them and returns an empty completion in that case.
 func (c *Client) ServiceCompletion(service string, request CompletionRequest) (string, error) {
+	return c.ServiceCompletionContext(context.Background(), service, request)
+}
+
+// ServiceCompletionContext is ServiceCompletion with a context for cancellation and deadlines.
+func (c *Client) ServiceCompletionContext(ctx context.Context, service string, request CompletionRequest) (string, error) {
 	url := fmt.Sprintf("/%s/bridge/v1/completion/service/%s/nostream", aiPluginID, service)
-	return c.doCompletionRequest(url, request)
+	return c.doCompletionRequest(ctx, url, request)
+}
+
+// ServiceCompletionWithUsage is ServiceCompletion but also returns token usage and finish-reason
+// metadata alongside the completion text.
+func (c *Client) ServiceCompletionWithUsage(service string, request CompletionRequest) (*CompletionResult, error) {
+	return c.ServiceCompletionWithUsageContext(context.Background(), service, request)
+}
+
+// ServiceCompletionWithUsageContext is ServiceCompletionWithUsage with a context for cancellation and deadlines.
+func (c *Client) ServiceCompletionWithUsageContext(ctx context.Context, service string, request CompletionRequest) (*CompletionResult, error) {
+	url := fmt.Sprintf("/%s/bridge/v1/completion/service/%s/nostream", aiPluginID, service)
+	return c.doCompletionRequestWithUsage(ctx, url, request)
 }
 
 // AgentCompletionStream makes a streaming completion request to a specific agent by Bot ID.
 // The agent parameter should be the Mattermost Bot User ID (an immutable identifier).
 // Returns a TextStreamResult with a Stream channel for processing events.
 func (c *Client) AgentCompletionStream(agent string, request CompletionRequest) (*llm.TextStreamResult, error) {
+	return c.AgentCompletionStreamContext(context.Background(), agent, request)
+}
+
+// AgentCompletionStreamContext is AgentCompletionStream with a context for cancellation and deadlines.
+// Cancelling ctx closes the underlying response body, stops the stream, and emits a terminal
+// llm.EventTypeError event before the Stream channel is closed, delivered to a caller that is
+// waiting on Stream at that point.
+func (c *Client) AgentCompletionStreamContext(ctx context.Context, agent string, request CompletionRequest) (*llm.TextStreamResult, error) {
 	url := fmt.Sprintf("/%s/bridge/v1/completion/agent/%s", aiPluginID, agent)
-	return c.doStreamingRequest(url, request)
+	return c.doStreamingRequest(ctx, url, request)
 }
 
 // ServiceCompletionStream makes a streaming completion request to a specific service.
 // The service parameter can be either a service ID or name (e.g., "openai", "anthropic").
 // Returns a TextStreamResult with a Stream channel for processing events.
 func (c *Client) ServiceCompletionStream(service string, request CompletionRequest) (*llm.TextStreamResult, error) {
-	url := fmt.Sprintf("/%s/bridge/v1/completion/service/%s", aiPluginID, service)
-	return c.doStreamingRequest(url, request)
+	return c.ServiceCompletionStreamContext(context.Background(), service, request)
 }
 
-// doCompletionRequest performs a non-streaming completion request
-func (c *Client) doCompletionRequest(url string, request CompletionRequest) (string, error) {
-	// Marshal the request body
-	body, err := json.Marshal(request)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
+// ServiceCompletionStreamContext is ServiceCompletionStream with a context for cancellation and deadlines.
+// Cancelling ctx closes the underlying response body, stops the stream, and emits a terminal
+// llm.EventTypeError event before the Stream channel is closed, delivered to a caller that is
+// waiting on Stream at that point.
+func (c *Client) ServiceCompletionStreamContext(ctx context.Context, service string, request CompletionRequest) (*llm.TextStreamResult, error) {
+	url := fmt.Sprintf("/%s/bridge/v1/completion/service/%s", aiPluginID, service)
+	return c.doStreamingRequest(ctx, url, request)
+}
 
-	// Create the HTTP request
-	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+// doCompletionRequest performs a non-streaming completion request, discarding usage and
+// finish-reason metadata for callers that only want the completion text.
+func (c *Client) doCompletionRequest(ctx context.Context, url string, request CompletionRequest) (string, error) {
+	result, err := c.doCompletionRequestWithUsage(ctx, url, request)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", err
 	}
+	return result.Completion, nil
+}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-
-	// Make the request
-	resp, err := c.httpClient.Do(req)
+// doCompletionRequestWithUsage performs a non-streaming completion request and returns the
+// completion text together with token usage and finish-reason metadata. Transient failures
+// (429s, 5xx responses, and connection errors) are retried per the client's retry policy.
+func (c *Client) doCompletionRequestWithUsage(ctx context.Context, url string, request CompletionRequest) (*CompletionResult, error) {
+	// Marshal the request body
+	body, err := json.Marshal(request)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Read the response body
-	respBody, err := io.ReadAll(resp.Body)
+	resp, respBody, err := c.doRequestWithRetry(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
 	// Check for error status codes
 	if resp.StatusCode != http.StatusOK {
 		var errResp ErrorResponse
 		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return "", fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
+			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
 		}
-		return "", fmt.Errorf("request failed with status %d: %s", resp.StatusCode, errResp.Error)
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, errResp.Error)
 	}
 
 	// Parse the success response
 	var completionResp CompletionResponse
 	if err := json.Unmarshal(respBody, &completionResp); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &CompletionResult{
+		Completion:   completionResp.Completion,
+		Usage:        completionResp.Usage,
+		FinishReason: completionResp.FinishReason,
+		ToolCalls:    completionResp.ToolCalls,
+	}, nil
+}
+
+// connectStream establishes the streaming HTTP request, retrying connection failures and
+// retryable status codes per the client's retry policy. It returns the live response with its
+// body unread, ready for the caller to either inspect an error status or start scanning the
+// SSE stream.
+func (c *Client) connectStream(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if attempt >= c.retry.maxRetries {
+				return nil, fmt.Errorf("failed to execute request: %w", err)
+			}
+			if waitErr := c.waitBeforeRetry(ctx, attempt, nil); waitErr != nil {
+				return nil, waitErr
+			}
+			continue
+		}
+
+		if c.retry.isRetryableStatus(resp.StatusCode) && attempt < c.retry.maxRetries {
+			resp.Body.Close()
+			if waitErr := c.waitBeforeRetry(ctx, attempt, resp.Header); waitErr != nil {
+				return nil, waitErr
+			}
+			continue
+		}
+
+		return resp, nil
 	}
+}
 
-	return completionResp.Completion, nil
+// trySend delivers event on stream, but gives up once ctx is done and no consumer is waiting
+// to receive. Without this guard, a consumer that cancels ctx and stops draining stream (the
+// natural `select { case <-ctx.Done(): return; case e := <-stream: ... }` pattern) would wedge
+// this send forever, leaking both the goroutine and its underlying connection. A consumer that
+// is already waiting on stream always gets the event, even after ctx is done.
+func trySend(ctx context.Context, stream chan<- llm.TextStreamEvent, event llm.TextStreamEvent) {
+	select {
+	case stream <- event:
+		return
+	default:
+	}
+	select {
+	case stream <- event:
+	case <-ctx.Done():
+	}
 }
 
-// doStreamingRequest performs a streaming completion request and returns a TextStreamResult
-func (c *Client) doStreamingRequest(url string, request CompletionRequest) (*llm.TextStreamResult, error) {
+// doStreamingRequest performs a streaming completion request and returns a TextStreamResult.
+// The returned stream is tied to ctx: cancelling ctx (or its deadline elapsing) closes the
+// response body, which unblocks the scanning goroutine, and a terminal llm.EventTypeError
+// event carrying ctx.Err() is sent before the Stream channel is closed. That event reaches a
+// caller that is waiting on Stream when it is sent; it is dropped if nobody is receiving.
+//
+// Connection failures and retryable status codes are retried, per the client's retry policy,
+// only up until the point where a 200 response is established; once streaming begins, a
+// failure is surfaced as a terminal event rather than retried, since retrying mid-stream
+// would duplicate tokens already delivered to the caller.
+func (c *Client) doStreamingRequest(ctx context.Context, url string, request CompletionRequest) (*llm.TextStreamResult, error) {
 	// Marshal the request body
 	body, err := json.Marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	// Create the HTTP request
-	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	resp, err := c.connectStream(ctx, url, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "text/event-stream")
-
-	// Make the request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
 
 	// Ensure body is closed in all paths
@@ -141,13 +254,37 @@ func (c *Client) doStreamingRequest(url string, request CompletionRequest) (*llm
 	// Create a channel for the stream
 	stream := make(chan llm.TextStreamEvent)
 
+	// done signals the watcher goroutine to stop once the reader goroutine below has finished,
+	// so it doesn't close resp.Body out from under a request that already completed normally.
+	done := make(chan struct{})
+
+	// Closing resp.Body unblocks scanner.Scan() below, so cancellation is handled by racing
+	// ctx.Done() against the reader goroutine's normal completion.
+	go func() {
+		select {
+		case <-ctx.Done():
+			resp.Body.Close()
+		case <-done:
+		}
+	}()
+
 	// Start a goroutine to read the SSE stream and populate the channel
 	go func() {
+		defer close(done)
 		defer resp.Body.Close()
 		defer close(stream)
 
 		scanner := bufio.NewScanner(resp.Body)
 		for scanner.Scan() {
+			// Stop promptly on cancellation instead of processing a partially drained buffer.
+			if ctx.Err() != nil {
+				trySend(ctx, stream, llm.TextStreamEvent{
+					Type:  llm.EventTypeError,
+					Value: ctx.Err(),
+				})
+				return
+			}
+
 			line := scanner.Text()
 
 			// SSE lines start with "data: "
@@ -167,15 +304,18 @@ func (c *Client) doStreamingRequest(url string, request CompletionRequest) (*llm
 			var event llm.TextStreamEvent
 			if err := json.Unmarshal([]byte(data), &event); err != nil {
 				// Send an error event
-				stream <- llm.TextStreamEvent{
+				trySend(ctx, stream, llm.TextStreamEvent{
 					Type:  llm.EventTypeError,
 					Value: fmt.Errorf("error parsing stream event: %w", err),
-				}
+				})
 				return
 			}
 
-			// Send the event to the channel
-			stream <- event
+			// Send the event to the channel. llm.EventTypeUsage (token counts and finish
+			// reason, sent last before llm.EventTypeEnd) and llm.EventTypeToolCalls (a
+			// requested tool invocation) are forwarded like any other event; it's up to
+			// the caller to execute the tool and start a follow-up request with the result.
+			trySend(ctx, stream, event)
 
 			// If this is an end or error event, stop reading
 			if event.Type == llm.EventTypeEnd || event.Type == llm.EventTypeError {
@@ -184,10 +324,20 @@ func (c *Client) doStreamingRequest(url string, request CompletionRequest) (*llm
 		}
 
 		if err := scanner.Err(); err != nil {
-			stream <- llm.TextStreamEvent{
+			// When ctx cancellation closed resp.Body, Scanner.Err() reports the resulting
+			// read error, so surface ctx.Err() in that case instead of that generic
+			// closed-connection error.
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				trySend(ctx, stream, llm.TextStreamEvent{
+					Type:  llm.EventTypeError,
+					Value: ctxErr,
+				})
+				return
+			}
+			trySend(ctx, stream, llm.TextStreamEvent{
 				Type:  llm.EventTypeError,
 				Value: fmt.Errorf("error reading stream: %w", err),
-			}
+			})
 		}
 	}()
 
@@ -0,0 +1,159 @@
+// Copyright (c) 2023-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bridgeclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-ai/llm"
+)
+
+// TestDoStreamingRequestAbandonedConsumerDoesNotLeakGoroutine is a regression test for a
+// goroutine leak: cancelling ctx and never reading from the returned Stream (the natural
+// "select on ctx.Done(), give up on the stream" pattern) used to wedge the reader goroutine
+// forever on an unguarded channel send. Closing the connection isn't enough to catch this --
+// a separate watcher goroutine already closes resp.Body on ctx.Done() regardless -- so this
+// test counts goroutines directly rather than asserting on server.Close().
+func TestDoStreamingRequestAbandonedConsumerDoesNotLeakGoroutine(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "data: {}\n\n")
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		// Hold the connection open until the client goes away, like a real agent stream
+		// that keeps producing events the abandoned consumer never asked to stop.
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	baseline := settledGoroutineCount()
+
+	client := &Client{retry: defaultRetryConfig()}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if _, err := client.doStreamingRequest(ctx, server.URL, CompletionRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Give the reader goroutine a chance to scan the already-flushed event and block on the
+	// send to stream, so cancelling below races an in-flight send rather than a fresh one.
+	time.Sleep(50 * time.Millisecond)
+
+	// Simulate a caller that cancels and walks away without ever reading Stream.
+	cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if settledGoroutineCount() <= baseline {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutine count did not return to baseline (%d) within 2s: reader goroutine leaked", baseline)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// settledGoroutineCount forces a GC before sampling, so recently-exited goroutines have had a
+// chance to be reaped and don't produce a flaky over-count.
+func settledGoroutineCount() int {
+	runtime.GC()
+	return runtime.NumGoroutine()
+}
+
+// TestAgentCompletionWithUsagePopulatesUsageAndFinishReason confirms doCompletionRequestWithUsage
+// actually unmarshals Usage and FinishReason off the response JSON into CompletionResult,
+// rather than just carrying the field through the struct definitions untested.
+func TestAgentCompletionWithUsagePopulatesUsageAndFinishReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(CompletionResponse{
+			Completion: "the answer",
+			Usage: &Usage{
+				PromptTokens:     12,
+				CompletionTokens: 34,
+				TotalTokens:      46,
+			},
+			FinishReason: FinishReasonStop,
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	result, err := client.AgentCompletionWithUsage("agent-a", CompletionRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Completion != "the answer" {
+		t.Fatalf("Completion = %q, want %q", result.Completion, "the answer")
+	}
+	if result.FinishReason != FinishReasonStop {
+		t.Fatalf("FinishReason = %q, want %q", result.FinishReason, FinishReasonStop)
+	}
+	if result.Usage == nil {
+		t.Fatal("Usage = nil, want non-nil")
+	}
+	if result.Usage.PromptTokens != 12 || result.Usage.CompletionTokens != 34 || result.Usage.TotalTokens != 46 {
+		t.Fatalf("Usage = %+v, want {12 34 46}", result.Usage)
+	}
+}
+
+// TestDoStreamingRequestForwardsUsageEventBeforeEnd confirms an llm.EventTypeUsage event sent
+// just ahead of llm.EventTypeEnd (the documented token-usage-reporting position) survives
+// doStreamingRequest's forwarding loop intact and in order, rather than being dropped or
+// reordered by the scanner/channel plumbing.
+func TestDoStreamingRequestForwardsUsageEventBeforeEnd(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, sseEvent(t, llm.TextStreamEvent{
+			Type:  llm.EventTypeUsage,
+			Value: llm.TokenUsage{InputTokens: 12, OutputTokens: 34},
+		}))
+		fmt.Fprint(w, sseEvent(t, llm.TextStreamEvent{Type: llm.EventTypeEnd}))
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := &Client{retry: defaultRetryConfig()}
+
+	result, err := client.doStreamingRequest(context.Background(), server.URL, CompletionRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gotTypes []llm.EventType
+	var usageValue any
+	for event := range result.Stream {
+		gotTypes = append(gotTypes, event.Type)
+		if event.Type == llm.EventTypeUsage {
+			usageValue = event.Value
+		}
+	}
+
+	if len(gotTypes) != 2 || gotTypes[0] != llm.EventTypeUsage || gotTypes[1] != llm.EventTypeEnd {
+		t.Fatalf("event order = %v, want [EventTypeUsage EventTypeEnd]", gotTypes)
+	}
+
+	usage, ok := usageValue.(map[string]interface{})
+	if !ok {
+		t.Fatalf("usage event Value = %#v (%T), want a decoded JSON object", usageValue, usageValue)
+	}
+	if usage["input_tokens"] != float64(12) || usage["output_tokens"] != float64(34) {
+		t.Fatalf("usage event Value = %+v, want input_tokens=12, output_tokens=34", usage)
+	}
+}
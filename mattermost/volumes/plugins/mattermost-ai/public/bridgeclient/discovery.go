@@ -4,34 +4,32 @@
 package bridgeclient
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 )
 
 // GetAgents retrieves all available agents from the bridge API.
 // If userID is provided, only agents accessible to that user are returned.
 func (c *Client) GetAgents(userID string) ([]BridgeAgentInfo, error) {
+	return c.GetAgentsContext(context.Background(), userID)
+}
+
+// GetAgentsContext is GetAgents with a context for cancellation and deadlines. Transient
+// failures (429s, 5xx responses, and connection errors) are retried per the client's retry
+// policy.
+func (c *Client) GetAgentsContext(ctx context.Context, userID string) ([]BridgeAgentInfo, error) {
 	url := fmt.Sprintf("/%s/bridge/v1/agents", aiPluginID)
 	if userID != "" {
 		url = fmt.Sprintf("%s?user_id=%s", url, userID)
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	resp, respBody, err := c.doRequestWithRetry(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -53,25 +51,23 @@ func (c *Client) GetAgents(userID string) ([]BridgeAgentInfo, error) {
 // GetServices retrieves all available services from the bridge API.
 // If userID is provided, only services accessible to that user (via their permitted bots) are returned.
 func (c *Client) GetServices(userID string) ([]BridgeServiceInfo, error) {
+	return c.GetServicesContext(context.Background(), userID)
+}
+
+// GetServicesContext is GetServices with a context for cancellation and deadlines. Transient
+// failures (429s, 5xx responses, and connection errors) are retried per the client's retry
+// policy.
+func (c *Client) GetServicesContext(ctx context.Context, userID string) ([]BridgeServiceInfo, error) {
 	url := fmt.Sprintf("/%s/bridge/v1/services", aiPluginID)
 	if userID != "" {
 		url = fmt.Sprintf("%s?user_id=%s", url, userID)
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	resp, respBody, err := c.doRequestWithRetry(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -0,0 +1,186 @@
+// Copyright (c) 2023-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bridgeclient
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mattermost/mattermost-plugin-ai/llm"
+)
+
+// AgentResult is the outcome of a single agent's completion within a MultiAgentCompletion call.
+// AgentID is always set; otherwise either Err is set or the completion fields are, never both.
+type AgentResult struct {
+	AgentID      string
+	Completion   string
+	Usage        *Usage
+	FinishReason FinishReason
+	ToolCalls    []ToolCall
+	Err          error
+}
+
+// AgentStreamEvent tags a streaming event with the agent that produced it, so events from
+// multiple concurrent agent streams can be multiplexed onto a single channel.
+type AgentStreamEvent struct {
+	AgentID string
+	Event   llm.TextStreamEvent
+}
+
+// MultiAgentStreamResult is the result of a streaming fan-out request: a single channel
+// multiplexing events from every agent, tagged with their originating agent ID.
+type MultiAgentStreamResult struct {
+	Stream chan AgentStreamEvent
+}
+
+// fanoutConfig controls how MultiAgentCompletion and its streaming equivalent dispatch
+// requests across agents.
+type fanoutConfig struct {
+	concurrency          int
+	cancelOnFirstSuccess bool
+}
+
+// FanoutOption configures a MultiAgentCompletion or MultiAgentCompletionStream call.
+type FanoutOption func(*fanoutConfig)
+
+// WithFanoutConcurrency bounds the number of agent requests dispatched at once. Defaults to
+// the number of agents, i.e. unbounded.
+func WithFanoutConcurrency(concurrency int) FanoutOption {
+	return func(cfg *fanoutConfig) {
+		cfg.concurrency = concurrency
+	}
+}
+
+// WithEarlyCancelOnFirstSuccess cancels the remaining in-flight agent requests as soon as one
+// agent completes successfully, for "race the fastest agent" workflows. The default is to wait
+// for every agent to finish.
+func WithEarlyCancelOnFirstSuccess() FanoutOption {
+	return func(cfg *fanoutConfig) {
+		cfg.cancelOnFirstSuccess = true
+	}
+}
+
+func newFanoutConfig(numAgents int, opts []FanoutOption) fanoutConfig {
+	cfg := fanoutConfig{concurrency: numAgents}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.concurrency <= 0 {
+		cfg.concurrency = numAgents
+	}
+	return cfg
+}
+
+// MultiAgentCompletion broadcasts request to every agent in agents concurrently and collects
+// each agent's result. One AgentResult is returned per agent, in the same order as agents; a
+// per-agent failure is reported in AgentResult.Err rather than failing the whole call.
+func (c *Client) MultiAgentCompletion(agents []string, request CompletionRequest, opts ...FanoutOption) ([]AgentResult, error) {
+	return c.MultiAgentCompletionContext(context.Background(), agents, request, opts...)
+}
+
+// MultiAgentCompletionContext is MultiAgentCompletion with a context for cancellation and
+// deadlines, applied to every dispatched agent request.
+func (c *Client) MultiAgentCompletionContext(ctx context.Context, agents []string, request CompletionRequest, opts ...FanoutOption) ([]AgentResult, error) {
+	cfg := newFanoutConfig(len(agents), opts)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]AgentResult, len(agents))
+	sem := make(chan struct{}, cfg.concurrency)
+
+	var wg sync.WaitGroup
+	for i, agent := range agents {
+		wg.Add(1)
+		go func(i int, agent string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := c.AgentCompletionWithUsageContext(ctx, agent, request)
+			if err != nil {
+				results[i] = AgentResult{AgentID: agent, Err: err}
+				return
+			}
+			results[i] = AgentResult{
+				AgentID:      agent,
+				Completion:   result.Completion,
+				Usage:        result.Usage,
+				FinishReason: result.FinishReason,
+				ToolCalls:    result.ToolCalls,
+			}
+
+			if cfg.cancelOnFirstSuccess {
+				cancel()
+			}
+		}(i, agent)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// MultiAgentCompletionStream broadcasts request to every agent in agents concurrently and
+// multiplexes their streaming events onto a single channel, each tagged with its originating
+// agent ID. A per-agent connection failure is delivered as an llm.EventTypeError event for
+// that agent rather than failing the whole call.
+func (c *Client) MultiAgentCompletionStream(agents []string, request CompletionRequest, opts ...FanoutOption) (*MultiAgentStreamResult, error) {
+	return c.MultiAgentCompletionStreamContext(context.Background(), agents, request, opts...)
+}
+
+// MultiAgentCompletionStreamContext is MultiAgentCompletionStream with a context for
+// cancellation and deadlines, applied to every dispatched agent stream.
+func (c *Client) MultiAgentCompletionStreamContext(ctx context.Context, agents []string, request CompletionRequest, opts ...FanoutOption) (*MultiAgentStreamResult, error) {
+	cfg := newFanoutConfig(len(agents), opts)
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	out := make(chan AgentStreamEvent)
+	sem := make(chan struct{}, cfg.concurrency)
+
+	var wg sync.WaitGroup
+	for _, agent := range agents {
+		wg.Add(1)
+		go func(agent string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := c.AgentCompletionStreamContext(ctx, agent, request)
+			if err != nil {
+				// Guard against a caller that cancelled ctx and stopped draining out: without
+				// this select, the send below would block forever, leaking this goroutine.
+				select {
+				case out <- AgentStreamEvent{AgentID: agent, Event: llm.TextStreamEvent{Type: llm.EventTypeError, Value: err}}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			for event := range result.Stream {
+				// Same abandoned-consumer guard as above; stop draining this agent's
+				// stream too once ctx is done instead of blocking on a send nobody reads.
+				select {
+				case out <- AgentStreamEvent{AgentID: agent, Event: event}:
+				case <-ctx.Done():
+					return
+				}
+				if cfg.cancelOnFirstSuccess && event.Type == llm.EventTypeEnd {
+					cancel()
+				}
+			}
+		}(agent)
+	}
+
+	// Close out only once every agent's stream has finished, then release ctx's resources.
+	go func() {
+		wg.Wait()
+		cancel()
+		close(out)
+	}()
+
+	return &MultiAgentStreamResult{Stream: out}, nil
+}
@@ -0,0 +1,300 @@
+// Copyright (c) 2023-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bridgeclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-ai/llm"
+)
+
+// relativeURLRoundTripper resolves the relative paths bridgeclient builds (e.g.
+// "/mattermost-ai/bridge/v1/completion/agent/<id>/nostream") against a real httptest.Server,
+// standing in for the pluginAPIRoundTripper/appAPIRoundTripper used in production.
+type relativeURLRoundTripper struct {
+	base *url.URL
+}
+
+func (rt *relativeURLRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resolved := req.Clone(req.Context())
+	resolved.URL.Scheme = rt.base.Scheme
+	resolved.URL.Host = rt.base.Host
+	return http.DefaultTransport.RoundTrip(resolved)
+}
+
+func newTestClient(server *httptest.Server) *Client {
+	base, err := url.Parse(server.URL)
+	if err != nil {
+		panic(err)
+	}
+	client := &Client{retry: defaultRetryConfig()}
+	client.httpClient.Transport = &relativeURLRoundTripper{base: base}
+	return client
+}
+
+// agentFromPath extracts the agent ID from a bridge completion URL path, e.g.
+// "/mattermost-ai/bridge/v1/completion/agent/<id>/nostream" -> "<id>".
+func agentFromPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if p == "agent" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+func TestMultiAgentCompletionAggregatesPerAgentResultsInOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent := agentFromPath(r.URL.Path)
+		resp := CompletionResponse{Completion: "reply from " + agent}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	agents := []string{"agent-a", "agent-b", "agent-c"}
+
+	results, err := client.MultiAgentCompletion(agents, CompletionRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(agents) {
+		t.Fatalf("got %d results, want %d", len(results), len(agents))
+	}
+	for i, agent := range agents {
+		if results[i].AgentID != agent {
+			t.Fatalf("results[%d].AgentID = %q, want %q (order not preserved)", i, results[i].AgentID, agent)
+		}
+		if results[i].Err != nil {
+			t.Fatalf("results[%d].Err = %v, want nil", i, results[i].Err)
+		}
+		want := "reply from " + agent
+		if results[i].Completion != want {
+			t.Fatalf("results[%d].Completion = %q, want %q", i, results[i].Completion, want)
+		}
+	}
+}
+
+func TestMultiAgentCompletionPerAgentFailureDoesNotFailWholeCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent := agentFromPath(r.URL.Path)
+		if agent == "broken-agent" {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "boom"})
+			return
+		}
+		json.NewEncoder(w).Encode(CompletionResponse{Completion: "ok from " + agent})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	// Disable retries so the broken agent fails on the first attempt instead of after backoff.
+	client.retry.maxRetries = 0
+	agents := []string{"good-agent", "broken-agent"}
+
+	results, err := client.MultiAgentCompletion(agents, CompletionRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Err != nil {
+		t.Fatalf("good-agent: Err = %v, want nil", results[0].Err)
+	}
+	if results[1].Err == nil {
+		t.Fatalf("broken-agent: Err = nil, want an error")
+	}
+}
+
+func TestMultiAgentCompletionContextRespectsConcurrencyLimit(t *testing.T) {
+	const concurrency = 2
+
+	var (
+		mu       sync.Mutex
+		inFlight int
+		peak     int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		json.NewEncoder(w).Encode(CompletionResponse{Completion: "ok"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	agents := make([]string, 6)
+	for i := range agents {
+		agents[i] = fmt.Sprintf("agent-%d", i)
+	}
+
+	if _, err := client.MultiAgentCompletionContext(context.Background(), agents, CompletionRequest{}, WithFanoutConcurrency(concurrency)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if peak > concurrency {
+		t.Fatalf("peak concurrent requests = %d, want <= %d", peak, concurrency)
+	}
+}
+
+func TestMultiAgentCompletionContextCancelsRemainingAgentsOnFirstSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent := agentFromPath(r.URL.Path)
+		if agent == "fast-agent" {
+			json.NewEncoder(w).Encode(CompletionResponse{Completion: "first"})
+			return
+		}
+		// Slow agent: block long enough for the fast agent to finish and cancel first. The
+		// fallback branch is a safety net in case cancellation doesn't propagate -- it
+		// should never win the race if WithEarlyCancelOnFirstSuccess works correctly.
+		select {
+		case <-r.Context().Done():
+		case <-time.After(300 * time.Millisecond):
+			json.NewEncoder(w).Encode(CompletionResponse{Completion: "too slow"})
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	agents := []string{"fast-agent", "slow-agent"}
+
+	results, err := client.MultiAgentCompletionContext(context.Background(), agents, CompletionRequest{}, WithEarlyCancelOnFirstSuccess())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Completion != "first" {
+		t.Fatalf("fast-agent: Completion = %q, want %q", results[0].Completion, "first")
+	}
+	if results[1].Err == nil {
+		t.Fatalf("slow-agent: Err = nil, want a cancellation error once the fast agent won the race")
+	}
+}
+
+func sseEvent(t *testing.T, event llm.TextStreamEvent) string {
+	t.Helper()
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("failed to marshal event: %v", err)
+	}
+	return fmt.Sprintf("data: %s\n\n", data)
+}
+
+func TestMultiAgentCompletionStreamContextTagsEventsByAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent := agentFromPath(r.URL.Path)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, sseEvent(t, llm.TextStreamEvent{Type: llm.EventTypeText, Value: "hi from " + agent}))
+		fmt.Fprint(w, sseEvent(t, llm.TextStreamEvent{Type: llm.EventTypeEnd}))
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	agents := []string{"agent-a", "agent-b"}
+
+	result, err := client.MultiAgentCompletionStream(agents, CompletionRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gotTextByAgent := map[string]bool{}
+	endsByAgent := map[string]int{}
+	deadline := time.After(2 * time.Second)
+	for i := 0; i < 4; i++ {
+		select {
+		case evt, ok := <-result.Stream:
+			if !ok {
+				t.Fatalf("stream closed early after %d events", i)
+			}
+			switch evt.Event.Type {
+			case llm.EventTypeText:
+				gotTextByAgent[evt.AgentID] = true
+			case llm.EventTypeEnd:
+				endsByAgent[evt.AgentID]++
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for multiplexed stream events")
+		}
+	}
+
+	for _, agent := range agents {
+		if !gotTextByAgent[agent] {
+			t.Errorf("agent %q: no text event observed", agent)
+		}
+		if endsByAgent[agent] != 1 {
+			t.Errorf("agent %q: got %d end events, want 1", agent, endsByAgent[agent])
+		}
+	}
+
+	// The stream must close once every agent is done.
+	select {
+	case _, ok := <-result.Stream:
+		if ok {
+			t.Fatal("expected stream to be closed after all agents finished")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream did not close after all agents finished")
+	}
+}
+
+func TestMultiAgentCompletionStreamContextAbandonedConsumerDoesNotLeakGoroutine(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, sseEvent(t, llm.TextStreamEvent{Type: llm.EventTypeText, Value: "hi"}))
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	baseline := settledGoroutineCount()
+
+	client := newTestClient(server)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if _, err := client.MultiAgentCompletionStreamContext(ctx, []string{"agent-a", "agent-b"}, CompletionRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Give the per-agent reader goroutines time to scan their event and block trying to
+	// send it on out, then walk away without ever draining MultiAgentStreamResult.Stream.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if settledGoroutineCount() <= baseline {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutine count did not return to baseline (%d) within 2s: fan-out goroutine leaked", baseline)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2023-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bridgeclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryConfig controls the retry-with-backoff behavior applied to transient failures.
+type retryConfig struct {
+	maxRetries        int
+	baseBackoff       time.Duration
+	maxBackoff        time.Duration
+	retryableStatuses map[int]struct{}
+}
+
+func defaultRetryConfig() retryConfig {
+	return retryConfig{
+		maxRetries:  3,
+		baseBackoff: 500 * time.Millisecond,
+		maxBackoff:  30 * time.Second,
+		retryableStatuses: map[int]struct{}{
+			http.StatusTooManyRequests:     {},
+			http.StatusInternalServerError: {},
+			http.StatusBadGateway:          {},
+			http.StatusServiceUnavailable:  {},
+			http.StatusGatewayTimeout:      {},
+		},
+	}
+}
+
+func (cfg retryConfig) isRetryableStatus(code int) bool {
+	_, ok := cfg.retryableStatuses[code]
+	return ok
+}
+
+// ClientOption configures optional behavior on a Client created by NewClient or NewClientFromApp.
+type ClientOption func(*Client)
+
+// WithMaxRetries sets the maximum number of retry attempts for transient failures (429 and
+// 5xx responses, or connection errors). Defaults to 3. A value of 0 disables retries.
+func WithMaxRetries(maxRetries int) ClientOption {
+	return func(c *Client) {
+		c.retry.maxRetries = maxRetries
+	}
+}
+
+// WithBackoff sets the base and max durations used for the exponential backoff applied
+// between retry attempts. Defaults to 500ms base, 30s max. Actual delays are jittered.
+func WithBackoff(base, max time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retry.baseBackoff = base
+		c.retry.maxBackoff = max
+	}
+}
+
+// WithRetryableStatusCodes overrides the set of HTTP status codes that trigger a retry.
+// Defaults to 429, 500, 502, 503, and 504.
+func WithRetryableStatusCodes(codes ...int) ClientOption {
+	return func(c *Client) {
+		statuses := make(map[int]struct{}, len(codes))
+		for _, code := range codes {
+			statuses[code] = struct{}{}
+		}
+		c.retry.retryableStatuses = statuses
+	}
+}
+
+// retryAfterDuration parses the Retry-After header as either a number of seconds or an
+// HTTP-date, per RFC 9110. It reports ok=false if header is nil or the value is absent or
+// unparseable, in which case the caller should fall back to its own backoff schedule.
+func retryAfterDuration(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t), true
+	}
+
+	return 0, false
+}
+
+// backoffDuration returns the exponential backoff delay for the given zero-indexed attempt,
+// with full jitter, capped at cfg.maxBackoff.
+func backoffDuration(cfg retryConfig, attempt int) time.Duration {
+	ceiling := cfg.baseBackoff << uint(attempt) //nolint:gosec // attempt is bounded by maxRetries
+	if ceiling <= 0 || ceiling > cfg.maxBackoff {
+		ceiling = cfg.maxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// sleepWithContext waits for d, returning early with ctx.Err() if ctx is done first.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// waitBeforeRetry pauses for the Retry-After duration if the response carries one, otherwise
+// for the configured exponential backoff.
+func (c *Client) waitBeforeRetry(ctx context.Context, attempt int, header http.Header) error {
+	if d, ok := retryAfterDuration(header); ok {
+		return sleepWithContext(ctx, d)
+	}
+	return sleepWithContext(ctx, backoffDuration(c.retry, attempt))
+}
+
+// doRequestWithRetry executes the request built by newReq, retrying on connection errors and
+// on status codes in the client's retryable set, honoring a Retry-After response header when
+// present. The response body is always fully read and closed; callers get it back as a byte
+// slice rather than an open reader.
+func (c *Client) doRequestWithRetry(ctx context.Context, newReq func() (*http.Request, error)) (*http.Response, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if attempt >= c.retry.maxRetries {
+				return nil, nil, fmt.Errorf("failed to execute request: %w", err)
+			}
+			if waitErr := c.waitBeforeRetry(ctx, attempt, nil); waitErr != nil {
+				return nil, nil, waitErr
+			}
+			continue
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		if c.retry.isRetryableStatus(resp.StatusCode) && attempt < c.retry.maxRetries {
+			if waitErr := c.waitBeforeRetry(ctx, attempt, resp.Header); waitErr != nil {
+				return nil, nil, waitErr
+			}
+			continue
+		}
+
+		return resp, respBody, nil
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2023-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bridgeclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfterDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    http.Header
+		wantOK    bool
+		wantAbout time.Duration
+	}{
+		{
+			name:   "absent",
+			header: http.Header{},
+			wantOK: false,
+		},
+		{
+			name:      "seconds",
+			header:    http.Header{"Retry-After": []string{"5"}},
+			wantOK:    true,
+			wantAbout: 5 * time.Second,
+		},
+		{
+			name:      "http-date",
+			header:    http.Header{"Retry-After": []string{time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)}},
+			wantOK:    true,
+			wantAbout: 10 * time.Second,
+		},
+		{
+			name:   "unparseable",
+			header: http.Header{"Retry-After": []string{"not-a-valid-value"}},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := retryAfterDuration(tt.header)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !tt.wantOK {
+				return
+			}
+			// Allow a couple seconds of slack for the http-date case, which round-trips
+			// through time.Until and wall-clock time passing during the test.
+			delta := d - tt.wantAbout
+			if delta < -2*time.Second || delta > 2*time.Second {
+				t.Fatalf("duration = %v, want ~%v", d, tt.wantAbout)
+			}
+		})
+	}
+}
+
+func TestBackoffDuration(t *testing.T) {
+	cfg := retryConfig{baseBackoff: 100 * time.Millisecond, maxBackoff: time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		d := backoffDuration(cfg, attempt)
+		if d < 0 {
+			t.Fatalf("attempt %d: backoffDuration returned negative duration %v", attempt, d)
+		}
+		if d > cfg.maxBackoff {
+			t.Fatalf("attempt %d: backoffDuration %v exceeds maxBackoff %v", attempt, d, cfg.maxBackoff)
+		}
+	}
+}
+
+func TestBackoffDurationOverflowFallsBackToMaxBackoff(t *testing.T) {
+	cfg := retryConfig{baseBackoff: 100 * time.Millisecond, maxBackoff: time.Second}
+	// A large attempt overflows (or at least exceeds maxBackoff); backoffDuration must clamp
+	// to maxBackoff rather than return a negative or oversized duration.
+	d := backoffDuration(cfg, 62)
+	if d < 0 || d > cfg.maxBackoff {
+		t.Fatalf("backoffDuration at high attempt = %v, want within [0, %v]", d, cfg.maxBackoff)
+	}
+}
+
+func TestDoRequestWithRetrySucceedsAfterRetryableStatus(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	cfg := defaultRetryConfig()
+	cfg.baseBackoff = time.Millisecond
+	cfg.maxBackoff = 5 * time.Millisecond
+	client := &Client{retry: cfg}
+
+	resp, body, err := client.doRequestWithRetry(context.Background(), func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if string(body) != "ok" {
+		t.Fatalf("body = %q, want %q", body, "ok")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+}
+
+func TestDoRequestWithRetryExhaustsRetriesAndReturnsLastResponse(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := defaultRetryConfig()
+	cfg.maxRetries = 2
+	cfg.baseBackoff = time.Millisecond
+	cfg.maxBackoff = 5 * time.Millisecond
+	client := &Client{retry: cfg}
+
+	resp, _, err := client.doRequestWithRetry(context.Background(), func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", resp.StatusCode)
+	}
+	// maxRetries 2 means attempts 0, 1, 2: one initial try plus two retries.
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+}
+
+func TestDoRequestWithRetryDoesNotRetryNonRetryableStatus(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := &Client{retry: defaultRetryConfig()}
+
+	resp, _, err := client.doRequestWithRetry(context.Background(), func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("attempts = %d, want 1 (no retry)", got)
+	}
+}
+
+func TestDoRequestWithRetryHonorsRetryAfterHeader(t *testing.T) {
+	var attempts int32
+	var firstAttemptAt, secondAttemptAt time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			firstAttemptAt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		secondAttemptAt = time.Now()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := defaultRetryConfig()
+	// Make the fallback backoff distinguishably shorter than the Retry-After value, so a
+	// pass that ignored the header and used backoffDuration instead would finish too fast.
+	cfg.baseBackoff = time.Millisecond
+	cfg.maxBackoff = 5 * time.Millisecond
+	client := &Client{retry: cfg}
+
+	if _, _, err := client.doRequestWithRetry(context.Background(), func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, nil)
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gap := secondAttemptAt.Sub(firstAttemptAt); gap < 900*time.Millisecond {
+		t.Fatalf("retry happened after %v, want >= ~1s per Retry-After header", gap)
+	}
+}
+
+func TestDoRequestWithRetryStopsOnContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := defaultRetryConfig()
+	cfg.baseBackoff = time.Second
+	cfg.maxBackoff = time.Second
+	client := &Client{retry: cfg}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := client.doRequestWithRetry(ctx, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, nil)
+	})
+	if err == nil {
+		t.Fatal("expected error from cancelled context, got nil")
+	}
+}
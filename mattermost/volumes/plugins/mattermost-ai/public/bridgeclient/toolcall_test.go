@@ -0,0 +1,124 @@
+// Copyright (c) 2023-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bridgeclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAgentCompletionWithUsageRoundTripsToolCalls confirms the tool-calling wire format
+// actually round-trips: CompletionRequest.Tools/ToolChoice serialize onto the outgoing JSON
+// body under the documented keys, and a response's tool_calls deserialize into
+// CompletionResult.ToolCalls. A JSON tag typo on either side would otherwise go unnoticed.
+func TestAgentCompletionWithUsageRoundTripsToolCalls(t *testing.T) {
+	var capturedBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&capturedBody); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		json.NewEncoder(w).Encode(CompletionResponse{
+			FinishReason: FinishReasonToolCall,
+			ToolCalls: []ToolCall{
+				{ID: "call_1", Name: "get_weather", Arguments: `{"city":"Chicago"}`},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	request := CompletionRequest{
+		Posts: []Post{{Role: "user", Message: "What's the weather in Chicago?"}},
+		Tools: []ToolDefinition{
+			{
+				Name:        "get_weather",
+				Description: "Look up the current weather for a city",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"city": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+		ToolChoice: "auto",
+	}
+
+	result, err := client.AgentCompletionWithUsage("agent-a", request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Verify the outgoing request body actually carried Tools/ToolChoice under the wire
+	// format the bridge API expects.
+	tools, ok := capturedBody["tools"].([]interface{})
+	if !ok || len(tools) != 1 {
+		t.Fatalf(`request body["tools"] = %#v, want a single-element array`, capturedBody["tools"])
+	}
+	tool, ok := tools[0].(map[string]interface{})
+	if !ok || tool["name"] != "get_weather" {
+		t.Fatalf(`request body["tools"][0] = %#v, want name "get_weather"`, tools[0])
+	}
+	if capturedBody["tool_choice"] != "auto" {
+		t.Fatalf(`request body["tool_choice"] = %#v, want "auto"`, capturedBody["tool_choice"])
+	}
+
+	// Verify the response's tool_calls deserialized into CompletionResult.ToolCalls.
+	if result.FinishReason != FinishReasonToolCall {
+		t.Fatalf("FinishReason = %q, want %q", result.FinishReason, FinishReasonToolCall)
+	}
+	if len(result.ToolCalls) != 1 {
+		t.Fatalf("got %d tool calls, want 1", len(result.ToolCalls))
+	}
+	got := result.ToolCalls[0]
+	want := ToolCall{ID: "call_1", Name: "get_weather", Arguments: `{"city":"Chicago"}`}
+	if got != want {
+		t.Fatalf("ToolCalls[0] = %+v, want %+v", got, want)
+	}
+}
+
+// TestMultiAgentCompletionRoundTripsToolCalls confirms the same tool_calls response field
+// also reaches AgentResult.ToolCalls through the fan-out path, not just the direct
+// AgentCompletionWithUsage call.
+func TestMultiAgentCompletionRoundTripsToolCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(CompletionResponse{
+			FinishReason: FinishReasonToolCall,
+			ToolCalls: []ToolCall{
+				{ID: "call_1", Name: "get_weather", Arguments: `{"city":"Chicago"}`},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	request := CompletionRequest{
+		Tools: []ToolDefinition{
+			{Name: "get_weather", Parameters: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}},
+		},
+	}
+
+	results, err := client.MultiAgentCompletion([]string{"agent-a"}, request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if results[0].FinishReason != FinishReasonToolCall {
+		t.Fatalf("FinishReason = %q, want %q", results[0].FinishReason, FinishReasonToolCall)
+	}
+	if len(results[0].ToolCalls) != 1 {
+		t.Fatalf("got %d tool calls, want 1", len(results[0].ToolCalls))
+	}
+	got := results[0].ToolCalls[0]
+	want := ToolCall{ID: "call_1", Name: "get_weather", Arguments: `{"city":"Chicago"}`}
+	if got != want {
+		t.Fatalf("ToolCalls[0] = %+v, want %+v", got, want)
+	}
+}